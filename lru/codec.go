@@ -0,0 +1,38 @@
+package lru
+
+import (
+	"encoding/gob"
+	"io"
+)
+
+// Codec serializes and deserializes a cache snapshot for SaveTo/LoadFrom,
+// so callers can swap the default encoding/gob format for JSON, protobuf,
+// or anything else. A ShardedCache.SaveTo/LoadFrom calls Encode/Decode
+// once for the shard-count header and once more per shard, all on the
+// same underlying stream, so a Codec used with ShardedCache must be safe
+// to invoke repeatedly against one io.Reader/io.Writer without reading or
+// writing past its own message boundary; GobCodec's length-delimited wire
+// format satisfies this as long as the reader implements io.ByteReader
+// (encoding/gob otherwise wraps it in a read-ahead bufio.Reader, which is
+// why ShardedCache.LoadFrom hands every Decode call one shared
+// bufio.Reader), but a naive encoding/json.Decoder per call does not,
+// since it buffers ahead past the end of each document.
+type Codec interface {
+	Encode(w io.Writer, snapshot interface{}) error
+	Decode(r io.Reader, snapshot interface{}) error
+}
+
+// GobCodec is the default Codec, using encoding/gob. Values stored behind
+// an interface{} key or value must be registered with gob.Register before
+// they can round-trip through SaveTo/LoadFrom.
+type GobCodec struct{}
+
+// Encode writes snapshot to w using encoding/gob.
+func (GobCodec) Encode(w io.Writer, snapshot interface{}) error {
+	return gob.NewEncoder(w).Encode(snapshot)
+}
+
+// Decode reads snapshot from r using encoding/gob.
+func (GobCodec) Decode(r io.Reader, snapshot interface{}) error {
+	return gob.NewDecoder(r).Decode(snapshot)
+}
@@ -171,6 +171,38 @@ func TestRemoveExpiredMax(t *testing.T) {
 	}
 }
 
+func TestAddWithTTL(t *testing.T) {
+	t.Parallel()
+	lru := New(math.MaxInt64)
+	lru.Add("untouched", 1234, 1)
+	lru.AddWithTTL("myKey", 1234, 1, 100*time.Millisecond)
+	time.Sleep(200 * time.Millisecond)
+	if _, ok := lru.Get("myKey"); ok {
+		t.Fatal("TestAddWithTTL returned an expired item")
+	}
+	if _, ok := lru.Get("untouched"); !ok {
+		t.Fatal("TestAddWithTTL expired an entry added without a TTL")
+	}
+}
+
+func TestStartJanitor(t *testing.T) {
+	lru := New(1024)
+	lru.AddWithTTL("myKey", 1234, 20, 20*time.Millisecond)
+
+	// Cache is not safe for concurrent access, so unlike
+	// TestSyncCacheStartJanitor this test cannot poll Len() while the
+	// janitor goroutine is running. Instead it waits out the TTL, stops
+	// the janitor (which blocks until the goroutine has exited), and only
+	// then reads the cache again.
+	stop := lru.StartJanitor(10 * time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	stop()
+
+	if lru.Len() != 0 {
+		t.Fatalf("TestStartJanitor expected the janitor to reclaim the expired entry, got Len() == %d", lru.Len())
+	}
+}
+
 func TestRemoveExpiredNotExpired(t *testing.T) {
 	lru := New(math.MaxInt64)
 	lru.TTL = 100 * time.Millisecond
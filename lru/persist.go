@@ -0,0 +1,126 @@
+package lru
+
+import (
+	"container/list"
+	"io"
+	"time"
+)
+
+// EntrySnapshot is the serializable form of one cache entry used by
+// SaveTo/LoadFrom. Visited, Promoted and FreqCount back the optional
+// PolicySieve, Policy2Q and PolicyLFU eviction strategies; they are unused
+// (and always zero) under the default PolicyLRU.
+type EntrySnapshot[K comparable, V any] struct {
+	Key     K
+	Value   V
+	Size    int64
+	Expires time.Time
+
+	Visited   bool
+	Promoted  bool
+	FreqCount int
+}
+
+// cacheSnapshot is the serializable form of an entire TypedCache.
+type cacheSnapshot[K comparable, V any] struct {
+	MaxSize    int64
+	TTL        time.Duration
+	PolicyKind PolicyKind
+
+	// HasSieveHand and SieveHandKey capture PolicySieve's hand position,
+	// identifying the entry it points to by key since *list.Element isn't
+	// serializable. They are unused under other policies.
+	HasSieveHand bool
+	SieveHandKey K
+
+	// Entries is ordered most-recently-used first, matching the cache's
+	// internal recency list. Combined with PolicyKind and each entry's
+	// policy-specific bits, this lets LoadFrom restore identical eviction
+	// behavior regardless of which Policy was in use.
+	Entries []EntrySnapshot[K, V]
+}
+
+func (c *TypedCache[K, V]) codec() Codec {
+	if c.Codec != nil {
+		return c.Codec
+	}
+	return GobCodec{}
+}
+
+// SaveTo serializes the cache's keys, values, sizes, expiration times,
+// recency order and eviction-policy state (including SIEVE's hand
+// position) using Codec (encoding/gob by default).
+func (c *TypedCache[K, V]) SaveTo(w io.Writer) error {
+	snapshot := cacheSnapshot[K, V]{MaxSize: c.MaxSize, TTL: c.TTL, PolicyKind: c.policyKind}
+	if sp, ok := c.policy.(*sievePolicy); ok && sp.hand != nil {
+		snapshot.HasSieveHand = true
+		snapshot.SieveHandKey = sp.hand.Value.(*typedEntry[K, V]).key
+	}
+	if c.ll != nil {
+		for e := c.ll.Front(); e != nil; e = e.Next() {
+			kv := e.Value.(*typedEntry[K, V])
+			snapshot.Entries = append(snapshot.Entries, EntrySnapshot[K, V]{
+				Key:       kv.key,
+				Value:     kv.value,
+				Size:      kv.size,
+				Expires:   kv.expires,
+				Visited:   kv.visitedBit,
+				Promoted:  kv.promotedBit,
+				FreqCount: kv.freqCount,
+			})
+		}
+	}
+	return c.codec().Encode(w, &snapshot)
+}
+
+// LoadFrom replaces the cache's contents with a snapshot written by
+// SaveTo, restoring MaxSize, TTL, recency order, the eviction policy and
+// its per-entry state (including SIEVE's hand position). Entries whose
+// expiration time has passed by the time LoadFrom runs (including those
+// already expired when saved) are skipped. The previous contents are
+// discarded without calling OnEvicted.
+func (c *TypedCache[K, V]) LoadFrom(r io.Reader) error {
+	var snapshot cacheSnapshot[K, V]
+	if err := c.codec().Decode(r, &snapshot); err != nil {
+		return err
+	}
+
+	c.MaxSize = snapshot.MaxSize
+	c.TTL = snapshot.TTL
+	c.policyKind = snapshot.PolicyKind
+	c.policy = newPolicy(snapshot.PolicyKind)
+	c.ll = list.New()
+	c.cache = make(map[K]*list.Element)
+	c.Size = 0
+
+	now := time.Now()
+	// Entries are ordered most-recently-used first; push from the back
+	// forward so PushFront rebuilds the same order.
+	for i := len(snapshot.Entries) - 1; i >= 0; i-- {
+		entry := snapshot.Entries[i]
+		if !entry.Expires.IsZero() && now.After(entry.Expires) {
+			continue
+		}
+		e := &typedEntry[K, V]{
+			key:         entry.Key,
+			value:       entry.Value,
+			size:        entry.Size,
+			expires:     entry.Expires,
+			visitedBit:  entry.Visited,
+			promotedBit: entry.Promoted,
+			freqCount:   entry.FreqCount,
+		}
+		ele := c.ll.PushFront(e)
+		c.cache[entry.Key] = ele
+		c.Size += entry.Size
+	}
+
+	if snapshot.HasSieveHand {
+		if sp, ok := c.policy.(*sievePolicy); ok {
+			if ele, hit := c.cache[snapshot.SieveHandKey]; hit {
+				sp.hand = ele
+			}
+		}
+	}
+	return nil
+}
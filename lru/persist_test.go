@@ -0,0 +1,266 @@
+package lru
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+)
+
+// jsonCodec is a JSON-based Codec, used by tests to confirm that
+// SyncCache/ShardedCache actually honor a codec installed via SetCodec
+// instead of always going through GobCodec. Each message is prefixed with
+// its length so, unlike a bare json.Decoder, it stays safe to call
+// repeatedly against one stream the way ShardedCache.SaveTo/LoadFrom do.
+type jsonCodec struct{}
+
+func (jsonCodec) Encode(w io.Writer, snapshot interface{}) error {
+	body, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, uint32(len(body))); err != nil {
+		return err
+	}
+	_, err = w.Write(body)
+	return err
+}
+
+func (jsonCodec) Decode(r io.Reader, snapshot interface{}) error {
+	var n uint32
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return err
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return err
+	}
+	return json.Unmarshal(body, snapshot)
+}
+
+func TestTypedCacheSaveAndLoad(t *testing.T) {
+	c := NewTyped[string, string](1024)
+	c.Add("a", "one")
+	c.Add("b", "two")
+	c.Get("a") // moves "a" to the front
+
+	var buf bytes.Buffer
+	if err := c.SaveTo(&buf); err != nil {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+
+	restored := NewTyped[string, string](0)
+	if err := restored.LoadFrom(&buf); err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+
+	if restored.MaxSize != 1024 {
+		t.Fatalf("TestTypedCacheSaveAndLoad expected MaxSize 1024, got %d", restored.MaxSize)
+	}
+	if restored.Len() != 2 {
+		t.Fatalf("TestTypedCacheSaveAndLoad expected 2 restored entries, got %d", restored.Len())
+	}
+
+	// Recency order must survive the round trip: "a" was the most recently
+	// used entry before saving, so adding past capacity should evict "b"
+	// first. Checking "b" here (rather than before this point) matters:
+	// Get itself promotes a PolicyLRU hit, so checking it earlier would
+	// have reordered the list under test.
+	restored.MaxSize = 2
+	restored.Add("c", "three")
+	if _, ok := restored.Get("b"); ok {
+		t.Fatal("TestTypedCacheSaveAndLoad expected b to be evicted first")
+	}
+	if val, ok := restored.Get("a"); !ok || val != "one" {
+		t.Fatalf("TestTypedCacheSaveAndLoad expected a=one to survive, got %v, %v", val, ok)
+	}
+}
+
+func TestTypedCacheSaveAndLoadPreservesSievePolicy(t *testing.T) {
+	c := NewTypedWithPolicy[string, string](2, PolicySieve)
+	c.Add("a", "one")
+	c.Add("b", "two")
+	c.Get("a") // marks "a" visited; SIEVE does not reorder the list
+
+	var buf bytes.Buffer
+	if err := c.SaveTo(&buf); err != nil {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+
+	// LoadFrom must restore PolicySieve (and "a"'s visited bit) even
+	// though restored was constructed with the default PolicyLRU.
+	restored := NewTyped[string, string](0)
+	if err := restored.LoadFrom(&buf); err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+
+	restored.Add("c", "three") // "a" is visited, so the hand skips it and evicts "b"
+
+	if _, ok := restored.Get("b"); ok {
+		t.Fatal("TestTypedCacheSaveAndLoadPreservesSievePolicy expected b to be evicted")
+	}
+	if _, ok := restored.Get("a"); !ok {
+		t.Fatal("TestTypedCacheSaveAndLoadPreservesSievePolicy expected a to survive")
+	}
+}
+
+func TestTypedCacheLoadSkipsExpired(t *testing.T) {
+	c := NewTyped[string, string](0)
+	c.AddWithExpiration("stale", "gone", time.Now().Add(-time.Minute))
+	c.AddWithExpiration("fresh", "here", time.Now().Add(time.Hour))
+
+	var buf bytes.Buffer
+	if err := c.SaveTo(&buf); err != nil {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+
+	restored := NewTyped[string, string](0)
+	if err := restored.LoadFrom(&buf); err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+
+	if _, ok := restored.Get("stale"); ok {
+		t.Fatal("TestTypedCacheLoadSkipsExpired restored an already-expired entry")
+	}
+	if _, ok := restored.Get("fresh"); !ok {
+		t.Fatal("TestTypedCacheLoadSkipsExpired dropped a live entry")
+	}
+}
+
+func TestCacheSaveAndLoad(t *testing.T) {
+	gob.Register(1234)
+
+	c := New(1024)
+	c.Add("myKey", 1234, 20)
+
+	var buf bytes.Buffer
+	if err := c.SaveTo(&buf); err != nil {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+
+	restored := New(0)
+	if err := restored.LoadFrom(&buf); err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if val, ok := restored.Get("myKey"); !ok || val != 1234 {
+		t.Fatalf("TestCacheSaveAndLoad expected myKey=1234, got %v, %v", val, ok)
+	}
+}
+
+func TestSyncCacheSaveAndLoad(t *testing.T) {
+	gob.Register(1234)
+
+	c := NewSync(1024)
+	c.Add("myKey", 1234, 20)
+
+	var buf bytes.Buffer
+	if err := c.SaveTo(&buf); err != nil {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+
+	restored := NewSync(0)
+	if err := restored.LoadFrom(&buf); err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if val, ok := restored.Get("myKey"); !ok || val != 1234 {
+		t.Fatalf("TestSyncCacheSaveAndLoad expected myKey=1234, got %v, %v", val, ok)
+	}
+}
+
+func TestSyncCacheSaveAndLoadWithCodec(t *testing.T) {
+	c := NewSync(1024)
+	c.SetCodec(jsonCodec{})
+	c.Add("myKey", "myValue", 20)
+
+	var buf bytes.Buffer
+	if err := c.SaveTo(&buf); err != nil {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+	var snapshot cacheSnapshot[Key, interface{}]
+	if err := (jsonCodec{}).Decode(bytes.NewReader(buf.Bytes()), &snapshot); err != nil {
+		t.Fatalf("TestSyncCacheSaveAndLoadWithCodec expected the snapshot to decode as JSON: %v", err)
+	}
+
+	restored := NewSync(0)
+	restored.SetCodec(jsonCodec{})
+	if err := restored.LoadFrom(&buf); err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if val, ok := restored.Get("myKey"); !ok || val != "myValue" {
+		t.Fatalf("TestSyncCacheSaveAndLoadWithCodec expected myKey=myValue, got %v, %v", val, ok)
+	}
+}
+
+func TestShardedCacheSaveAndLoad(t *testing.T) {
+	gob.Register(1234)
+
+	c := NewSharded(1024, 4)
+	for i := 0; i < 10; i++ {
+		c.Add(i, i*100, 5)
+	}
+
+	var buf bytes.Buffer
+	if err := c.SaveTo(&buf); err != nil {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+
+	restored := NewSharded(1024, 4)
+	if err := restored.LoadFrom(&buf); err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if got := restored.Len(); got != 10 {
+		t.Fatalf("TestShardedCacheSaveAndLoad expected 10 restored entries, got %d", got)
+	}
+	for i := 0; i < 10; i++ {
+		if val, ok := restored.Get(i); !ok || val != i*100 {
+			t.Fatalf("TestShardedCacheSaveAndLoad expected key %d to restore to %d, got %v, %v", i, i*100, val, ok)
+		}
+	}
+}
+
+func TestShardedCacheSaveAndLoadWithCodec(t *testing.T) {
+	c := NewSharded(1024, 4)
+	c.SetCodec(jsonCodec{})
+	keys := []string{"k0", "k1", "k2", "k3", "k4", "k5", "k6", "k7", "k8", "k9"}
+	for _, k := range keys {
+		c.Add(k, k+"-value", 5)
+	}
+
+	var buf bytes.Buffer
+	if err := c.SaveTo(&buf); err != nil {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+	var count int
+	if err := (jsonCodec{}).Decode(bytes.NewReader(buf.Bytes()), &count); err != nil || count != 4 {
+		t.Fatalf("TestShardedCacheSaveAndLoadWithCodec expected the shard-count header to decode as JSON and equal 4, got %d, %v", count, err)
+	}
+
+	restored := NewSharded(1024, 4)
+	restored.SetCodec(jsonCodec{})
+	if err := restored.LoadFrom(&buf); err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	for _, k := range keys {
+		if val, ok := restored.Get(k); !ok || val != k+"-value" {
+			t.Fatalf("TestShardedCacheSaveAndLoadWithCodec expected key %s to restore to %s-value, got %v, %v", k, k, val, ok)
+		}
+	}
+}
+
+func TestShardedCacheLoadFromRejectsShardCountMismatch(t *testing.T) {
+	c := NewSharded(1024, 4)
+	c.Add("myKey", 1234, 20)
+
+	var buf bytes.Buffer
+	if err := c.SaveTo(&buf); err != nil {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+
+	restored := NewSharded(1024, 2)
+	if err := restored.LoadFrom(&buf); err == nil {
+		t.Fatal("TestShardedCacheLoadFromRejectsShardCountMismatch expected an error for mismatched shard counts")
+	}
+}
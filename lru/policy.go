@@ -0,0 +1,182 @@
+package lru
+
+import "container/list"
+
+// Policy decides which entry a cache evicts once it exceeds MaxSize, and
+// how an entry is treated when it is looked up with Get. The zero-value
+// Cache/TypedCache uses PolicyLRU; NewWithPolicy/NewTypedWithPolicy select
+// a different strategy.
+type Policy interface {
+	// onAccess runs on a cache hit, in place of the classic MoveToFront.
+	onAccess(ll *list.List, elem *list.Element)
+	// onInsert runs right after a new entry is pushed to the front of ll.
+	onInsert(ll *list.List, elem *list.Element)
+	// victim returns the element to evict next, or nil if ll is empty.
+	victim(ll *list.List) *list.Element
+}
+
+// PolicyKind selects one of the built-in Policy implementations for
+// NewWithPolicy and NewTypedWithPolicy.
+type PolicyKind int
+
+const (
+	// PolicyLRU evicts the least recently used entry. This is the default.
+	PolicyLRU PolicyKind = iota
+	// PolicySieve evicts using the SIEVE algorithm: a single "visited" bit
+	// per entry and a hand that sweeps the list looking for an unvisited
+	// entry to reclaim.
+	PolicySieve
+	// Policy2Q evicts from the set of entries that have only ever been
+	// accessed once before reaching for a recently-promoted entry.
+	Policy2Q
+	// PolicyLFU evicts the least frequently accessed entry.
+	PolicyLFU
+)
+
+func newPolicy(kind PolicyKind) Policy {
+	switch kind {
+	case PolicySieve:
+		return &sievePolicy{}
+	case Policy2Q:
+		return twoQPolicy{}
+	case PolicyLFU:
+		return lfuPolicy{}
+	default:
+		return lruPolicy{}
+	}
+}
+
+// visitBit is implemented by cache entries that carry SIEVE's visited bit.
+type visitBit interface {
+	visited() bool
+	setVisited(visited bool)
+}
+
+// promotable is implemented by cache entries that carry 2Q's promoted bit.
+type promotable interface {
+	promoted() bool
+	setPromoted(promoted bool)
+}
+
+// frequency is implemented by cache entries that carry an LFU access count.
+type frequency interface {
+	freq() int
+	bump()
+}
+
+// lruPolicy is the classic recency-list behaviour: Get moves the hit entry
+// to the front, and the victim is always the back of the list.
+type lruPolicy struct{}
+
+func (lruPolicy) onAccess(ll *list.List, elem *list.Element) { ll.MoveToFront(elem) }
+func (lruPolicy) onInsert(ll *list.List, elem *list.Element) {}
+func (lruPolicy) victim(ll *list.List) *list.Element         { return ll.Back() }
+
+// sievePolicy implements SIEVE (https://sievecache.com). Entries keep their
+// insertion position; Get only flips the visited bit. Eviction sweeps a
+// hand from its last position toward the head, clearing visited bits along
+// the way, and reclaims the first unvisited entry it finds.
+type sievePolicy struct {
+	hand *list.Element
+}
+
+func (p *sievePolicy) onAccess(ll *list.List, elem *list.Element) {
+	if vb, ok := elem.Value.(visitBit); ok {
+		vb.setVisited(true)
+	}
+}
+
+func (p *sievePolicy) onInsert(ll *list.List, elem *list.Element) {
+	// New entries are inserted at the head with visited already false
+	// (the zero value), so there is nothing to do here.
+}
+
+func (p *sievePolicy) victim(ll *list.List) *list.Element {
+	if ll.Len() == 0 {
+		return nil
+	}
+
+	e := p.hand
+	if e == nil {
+		e = ll.Back()
+	}
+	for {
+		vb, ok := e.Value.(visitBit)
+		if !ok || !vb.visited() {
+			break
+		}
+		vb.setVisited(false)
+		next := e.Prev()
+		if next == nil {
+			next = ll.Back()
+		}
+		e = next
+	}
+
+	prev := e.Prev()
+	if prev == nil || prev == e {
+		prev = nil
+	}
+	p.hand = prev
+	return e
+}
+
+// twoQPolicy approximates 2Q on top of a single recency list: an entry
+// starts out unpromoted (as if sitting in the A1 queue) and is promoted to
+// the Am queue the first time it is accessed again. Eviction always prefers
+// the oldest unpromoted entry, falling back to the oldest entry overall
+// once every entry has been promoted.
+type twoQPolicy struct{}
+
+func (twoQPolicy) onAccess(ll *list.List, elem *list.Element) {
+	if p, ok := elem.Value.(promotable); ok {
+		p.setPromoted(true)
+	}
+	ll.MoveToFront(elem)
+}
+
+func (twoQPolicy) onInsert(ll *list.List, elem *list.Element) {}
+
+func (twoQPolicy) victim(ll *list.List) *list.Element {
+	for e := ll.Back(); e != nil; e = e.Prev() {
+		if p, ok := e.Value.(promotable); ok && !p.promoted() {
+			return e
+		}
+	}
+	return ll.Back()
+}
+
+// lfuPolicy evicts the entry with the fewest accesses, using recency only
+// as a tiebreaker among equally-unpopular entries.
+type lfuPolicy struct{}
+
+func (lfuPolicy) onAccess(ll *list.List, elem *list.Element) {
+	if f, ok := elem.Value.(frequency); ok {
+		f.bump()
+	}
+	ll.MoveToFront(elem)
+}
+
+func (lfuPolicy) onInsert(ll *list.List, elem *list.Element) {
+	// Count the insert itself as the first access, so a freshly added
+	// entry isn't immediately the least-frequently-used victim.
+	if f, ok := elem.Value.(frequency); ok {
+		f.bump()
+	}
+}
+
+func (lfuPolicy) victim(ll *list.List) *list.Element {
+	var least *list.Element
+	leastFreq := -1
+	for e := ll.Back(); e != nil; e = e.Prev() {
+		n := 0
+		if f, ok := e.Value.(frequency); ok {
+			n = f.freq()
+		}
+		if leastFreq == -1 || n < leastFreq {
+			leastFreq = n
+			least = e
+		}
+	}
+	return least
+}
@@ -0,0 +1,105 @@
+package lru
+
+import "testing"
+
+func TestNewWithPolicyDefaultsToLRU(t *testing.T) {
+	c := New(2)
+	c.Add("a", 1, 1)
+	c.Add("b", 2, 1)
+	c.Get("a")
+	c.Add("c", 3, 1) // evicts "b", the least recently used entry
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("TestNewWithPolicyDefaultsToLRU expected b to be evicted")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("TestNewWithPolicyDefaultsToLRU expected a to survive")
+	}
+}
+
+func TestSievePolicyKeepsVisitedEntries(t *testing.T) {
+	c := NewWithPolicy(2, PolicySieve)
+	c.Add("a", 1, 1)
+	c.Add("b", 2, 1)
+	c.Get("a") // marks "a" visited; SIEVE does not reorder the list
+
+	c.Add("c", 3, 1) // "a" is visited, so the hand skips it and evicts "b"
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("TestSievePolicyKeepsVisitedEntries expected b to be evicted")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("TestSievePolicyKeepsVisitedEntries expected a to survive")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Fatal("TestSievePolicyKeepsVisitedEntries expected c to be present")
+	}
+}
+
+func Test2QPolicyPrefersUnpromotedVictim(t *testing.T) {
+	c := NewWithPolicy(2, Policy2Q)
+	c.Add("a", 1, 1)
+	c.Add("b", 2, 1)
+	c.Get("b") // promotes "b" out of the A1 queue
+
+	c.Add("c", 3, 1) // "a" was never re-accessed, so it is evicted first
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("Test2QPolicyPrefersUnpromotedVictim expected a to be evicted")
+	}
+	if _, ok := c.Get("b"); !ok {
+		t.Fatal("Test2QPolicyPrefersUnpromotedVictim expected b to survive")
+	}
+}
+
+func TestSievePolicyAddReplaceResetsVisited(t *testing.T) {
+	c := NewWithPolicy(2, PolicySieve)
+	c.Add("a", 1, 1)
+	c.Add("b", 2, 1)
+	c.Get("a")         // marks "a" visited
+	c.Add("a", 999, 1) // replacing "a" must clear its visited bit
+	c.Add("c", 3, 1)   // evicts "b", the untouched entry; hand now sits on "a"
+	c.Add("d", 4, 1)   // hand reaches "a" first: if its visited bit survived
+	// the replace it would be skipped, wrongly evicting "c" instead
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("TestSievePolicyAddReplaceResetsVisited expected a to be evicted")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Fatal("TestSievePolicyAddReplaceResetsVisited expected c to survive")
+	}
+}
+
+func Test2QPolicyAddReplaceResetsPromoted(t *testing.T) {
+	c := NewWithPolicy(2, Policy2Q)
+	c.Add("a", 1, 1)
+	c.Add("b", 2, 1)
+	c.Get("a")         // promotes "a" out of the A1 queue
+	c.Add("a", 999, 1) // replacing "a" must clear its promoted bit
+	c.Add("c", 3, 1)   // evicts "b", the never-promoted entry
+	c.Add("d", 4, 1)   // victim() scans from the back and finds "a" first: if its
+	// promoted bit survived the replace it would wrongly evict "c" instead
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("Test2QPolicyAddReplaceResetsPromoted expected a to be evicted")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Fatal("Test2QPolicyAddReplaceResetsPromoted expected c to survive")
+	}
+}
+
+func TestLFUPolicyEvictsLeastFrequentlyUsed(t *testing.T) {
+	c := NewWithPolicy(2, PolicyLFU)
+	c.Add("a", 1, 1)
+	c.Add("b", 2, 1)
+	c.Get("a")
+
+	c.Add("c", 3, 1) // "b" was never re-accessed, so it is evicted over "a"
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("TestLFUPolicyEvictsLeastFrequentlyUsed expected b to be evicted")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("TestLFUPolicyEvictsLeastFrequentlyUsed expected a to survive")
+	}
+}
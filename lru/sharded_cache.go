@@ -0,0 +1,257 @@
+package lru
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ShardedCache hashes keys across a fixed number of SyncCache shards to
+// reduce lock contention under concurrent access. MaxSize is distributed
+// proportionally across the shards.
+type ShardedCache struct {
+	shards []*SyncCache
+
+	// codecMu guards codec, since SetCodec can race with SaveTo/LoadFrom
+	// reading it for the shard-count header, unlike codec on a SyncCache's
+	// underlying Cache, which is already protected by SyncCache.mu.
+	codecMu sync.RWMutex
+	// codec mirrors whatever was last passed to SetCodec, so SaveTo/
+	// LoadFrom can encode the shard-count header in the same format as
+	// the shards themselves. A nil codec means GobCodec, the default.
+	codec Codec
+}
+
+// NewSharded creates a ShardedCache with numShards SyncCache shards,
+// splitting maxSize as evenly as possible across them. If numShards is
+// less than 1, a single shard is used.
+func NewSharded(maxSize int64, numShards int) *ShardedCache {
+	return newSharded(maxSize, numShards, PolicyLRU)
+}
+
+// NewShardedWithPolicy creates a ShardedCache whose shards evict using the
+// given PolicyKind (PolicySieve, Policy2Q or PolicyLFU) instead of the
+// default PolicyLRU. Each shard runs the policy independently, the same
+// way each shard tracks its own Stats.
+func NewShardedWithPolicy(maxSize int64, numShards int, kind PolicyKind) *ShardedCache {
+	return newSharded(maxSize, numShards, kind)
+}
+
+func newSharded(maxSize int64, numShards int, kind PolicyKind) *ShardedCache {
+	if numShards < 1 {
+		numShards = 1
+	}
+
+	perShard := maxSize / int64(numShards)
+	remainder := maxSize % int64(numShards)
+
+	shards := make([]*SyncCache, numShards)
+	for i := range shards {
+		size := perShard
+		if int64(i) < remainder {
+			size++
+		}
+		shards[i] = NewSyncWithPolicy(size, kind)
+	}
+
+	return &ShardedCache{shards: shards}
+}
+
+// fnvOffset32 and fnvPrime32 are FNV-1a's standard 32-bit constants (see
+// hash/fnv). shardFor inlines the algorithm instead of using hash/fnv
+// directly so it can hash without allocating a hash.Hash32, and uses no
+// seed so a key always maps to the same shard index across separately
+// constructed ShardedCaches, including across a SaveTo/LoadFrom round trip.
+const (
+	fnvOffset32 = 2166136261
+	fnvPrime32  = 16777619
+)
+
+// shardFor returns the shard responsible for key. Common key kinds are
+// hashed directly via an inlined FNV-1a, which (unlike
+// fmt.Sprintf("%v", key)) doesn't allocate; other key types fall back to
+// that formatting.
+func (s *ShardedCache) shardFor(key Key) *SyncCache {
+	h := uint32(fnvOffset32)
+
+	switch k := key.(type) {
+	case string:
+		h = hashString(h, k)
+	case int:
+		h = hashUint64(h, uint64(k))
+	case int8:
+		h = hashUint64(h, uint64(k))
+	case int16:
+		h = hashUint64(h, uint64(k))
+	case int32:
+		h = hashUint64(h, uint64(k))
+	case int64:
+		h = hashUint64(h, uint64(k))
+	case uint:
+		h = hashUint64(h, uint64(k))
+	case uint8:
+		h = hashUint64(h, uint64(k))
+	case uint16:
+		h = hashUint64(h, uint64(k))
+	case uint32:
+		h = hashUint64(h, uint64(k))
+	case uint64:
+		h = hashUint64(h, k)
+	case bool:
+		if k {
+			h = hashByte(h, 1)
+		} else {
+			h = hashByte(h, 0)
+		}
+	default:
+		h = hashString(h, fmt.Sprintf("%v", key))
+	}
+
+	return s.shards[h%uint32(len(s.shards))]
+}
+
+// hashByte folds a single byte into the running FNV-1a hash h.
+func hashByte(h uint32, b byte) uint32 {
+	return (h ^ uint32(b)) * fnvPrime32
+}
+
+// hashString folds s's bytes into the running FNV-1a hash h. Ranging over
+// the string directly, rather than converting it to a []byte first,
+// avoids allocating.
+func hashString(h uint32, s string) uint32 {
+	for i := 0; i < len(s); i++ {
+		h = hashByte(h, s[i])
+	}
+	return h
+}
+
+// hashUint64 folds v's 8 bytes into the running FNV-1a hash h without
+// needing a byte buffer.
+func hashUint64(h uint32, v uint64) uint32 {
+	for i := 0; i < 8; i++ {
+		h = hashByte(h, byte(v))
+		v >>= 8
+	}
+	return h
+}
+
+// Add adds a value to the cache.
+func (s *ShardedCache) Add(key Key, value interface{}, size int64) bool {
+	return s.shardFor(key).Add(key, value, size)
+}
+
+// Get looks up a key's value from the cache.
+func (s *ShardedCache) Get(key Key) (value interface{}, ok bool) {
+	return s.shardFor(key).Get(key)
+}
+
+// Remove removes the provided key from the cache.
+func (s *ShardedCache) Remove(key Key) {
+	s.shardFor(key).Remove(key)
+}
+
+// Len returns the aggregate number of items across all shards.
+func (s *ShardedCache) Len() int {
+	total := 0
+	for _, shard := range s.shards {
+		total += shard.Len()
+	}
+	return total
+}
+
+// Size returns the aggregate sum of cache entry sizes across all shards.
+func (s *ShardedCache) Size() int64 {
+	var total int64
+	for _, shard := range s.shards {
+		total += shard.Size()
+	}
+	return total
+}
+
+// Stats returns the aggregate hit/miss/eviction/expiration counters across
+// all shards.
+func (s *ShardedCache) Stats() Stats {
+	var total Stats
+	for _, shard := range s.shards {
+		st := shard.Stats()
+		total.Hits += st.Hits
+		total.Misses += st.Misses
+		total.Evictions += st.Evictions
+		total.Expirations += st.Expirations
+	}
+	return total
+}
+
+// SetOnEvicted installs fn as the eviction callback on every shard.
+func (s *ShardedCache) SetOnEvicted(fn func(key Key, value interface{}, reason EvictReason)) {
+	for _, shard := range s.shards {
+		shard.SetOnEvicted(fn)
+	}
+}
+
+// SetCodec installs codec as the SaveTo/LoadFrom serializer on every
+// shard, as well as on the shard-count header written by SaveTo, so a
+// ShardedCache snapshot is encoded in a single consistent format. A nil
+// codec restores the GobCodec default.
+func (s *ShardedCache) SetCodec(codec Codec) {
+	s.codecMu.Lock()
+	s.codec = codec
+	s.codecMu.Unlock()
+	for _, shard := range s.shards {
+		shard.SetCodec(codec)
+	}
+}
+
+// headerCodec returns the Codec used for the shard-count header, matching
+// whatever was last passed to SetCodec.
+func (s *ShardedCache) headerCodec() Codec {
+	s.codecMu.RLock()
+	defer s.codecMu.RUnlock()
+	if s.codec != nil {
+		return s.codec
+	}
+	return GobCodec{}
+}
+
+// SaveTo serializes every shard's snapshot to w, in shard order, preceded
+// by the shard count.
+func (s *ShardedCache) SaveTo(w io.Writer) error {
+	count := len(s.shards)
+	if err := s.headerCodec().Encode(w, &count); err != nil {
+		return err
+	}
+	for _, shard := range s.shards {
+		if err := shard.SaveTo(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadFrom replaces every shard's contents with a snapshot written by
+// SaveTo. The ShardedCache being restored into must already have the same
+// number of shards (i.e. have been constructed with the same numShards
+// passed to NewSharded/NewShardedWithPolicy), since reshaping shards would
+// require rehashing every key.
+func (s *ShardedCache) LoadFrom(r io.Reader) error {
+	// Share one buffered reader across the header and every shard, so a
+	// decoder that buffers a non-io.ByteReader (as encoding/gob does) cannot
+	// swallow bytes that belong to the next message.
+	if _, ok := r.(io.ByteReader); !ok {
+		r = bufio.NewReader(r)
+	}
+	var count int
+	if err := s.headerCodec().Decode(r, &count); err != nil {
+		return err
+	}
+	if count != len(s.shards) {
+		return fmt.Errorf("lru: snapshot has %d shards, but ShardedCache has %d", count, len(s.shards))
+	}
+	for _, shard := range s.shards {
+		if err := shard.LoadFrom(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
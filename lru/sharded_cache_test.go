@@ -0,0 +1,120 @@
+package lru
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+)
+
+func TestShardedCacheGetAdd(t *testing.T) {
+	c := NewSharded(1024, 4)
+	c.Add("myKey", 1234, 20)
+	if val, ok := c.Get("myKey"); !ok {
+		t.Fatal("TestShardedCacheGetAdd returned no match")
+	} else if val != 1234 {
+		t.Fatalf("TestShardedCacheGetAdd failed. Expected %d, got %v", 1234, val)
+	}
+}
+
+func TestShardedCacheLenAndSize(t *testing.T) {
+	c := NewSharded(1024, 4)
+	for i := 0; i < 10; i++ {
+		c.Add(i, i, 5)
+	}
+	if got := c.Len(); got != 10 {
+		t.Fatalf("TestShardedCacheLenAndSize expected Len() == 10, got %d", got)
+	}
+	if got := c.Size(); got != 50 {
+		t.Fatalf("TestShardedCacheLenAndSize expected Size() == 50, got %d", got)
+	}
+}
+
+func TestShardedCacheNewWithPolicy(t *testing.T) {
+	// A single shard makes eviction order deterministic regardless of
+	// which key hashes where.
+	c := NewShardedWithPolicy(2, 1, PolicySieve)
+	c.Add("a", 1, 1)
+	c.Add("b", 2, 1)
+	c.Get("a") // marks "a" visited; SIEVE does not reorder the list
+
+	c.Add("c", 3, 1) // "a" is visited, so the hand skips it and evicts "b"
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("TestShardedCacheNewWithPolicy expected b to be evicted")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("TestShardedCacheNewWithPolicy expected a to survive")
+	}
+}
+
+func TestShardedCacheShardForNoAlloc(t *testing.T) {
+	c := NewSharded(1024, 4)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		c.shardFor("myKey")
+		c.shardFor(42)
+	})
+	if allocs != 0 {
+		t.Fatalf("TestShardedCacheShardForNoAlloc expected 0 allocs/op hashing string and int keys, got %v", allocs)
+	}
+}
+
+func TestShardedCacheSingleShard(t *testing.T) {
+	c := NewSharded(100, 0)
+	if len(c.shards) != 1 {
+		t.Fatalf("TestShardedCacheSingleShard expected 1 shard, got %d", len(c.shards))
+	}
+}
+
+func TestShardedCacheStats(t *testing.T) {
+	c := NewSharded(1024, 4)
+	c.Add("myKey", 1234, 20)
+	c.Get("myKey")
+	c.Get("nonsense")
+
+	stats := c.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Fatalf("TestShardedCacheStats expected 1 hit and 1 miss, got %+v", stats)
+	}
+}
+
+func TestShardedCacheSetOnEvicted(t *testing.T) {
+	c := NewSharded(1024, 4)
+	var gotReason EvictReason
+	c.SetOnEvicted(func(key Key, value interface{}, reason EvictReason) {
+		gotReason = reason
+	})
+
+	c.Add("myKey", 1234, 20)
+	c.Remove("myKey")
+
+	if gotReason != EvictManual {
+		t.Fatalf("TestShardedCacheSetOnEvicted expected EvictManual, got %v", gotReason)
+	}
+}
+
+func TestShardedCacheSetCodecConcurrentWithSaveToNoRace(t *testing.T) {
+	c := NewSharded(1024, 4)
+	c.Add("myKey", 1234, 20)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.SetCodec(GobCodec{})
+		}()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.SaveTo(io.Discard)
+		}()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.LoadFrom(bytes.NewReader(nil))
+		}()
+	}
+	wg.Wait()
+}
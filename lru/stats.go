@@ -0,0 +1,39 @@
+package lru
+
+// EvictReason explains why OnEvicted was called for a removed entry.
+type EvictReason int
+
+const (
+	// EvictCapacity means the entry was evicted to keep Size within MaxSize.
+	EvictCapacity EvictReason = iota
+	// EvictExpired means the entry's TTL had elapsed.
+	EvictExpired
+	// EvictManual means the entry was removed by an explicit Remove call.
+	EvictManual
+	// EvictReplaced means the entry was replaced by a new Add for the same key.
+	EvictReplaced
+)
+
+// String returns a lower-case name for r, suitable for metric labels.
+func (r EvictReason) String() string {
+	switch r {
+	case EvictCapacity:
+		return "capacity"
+	case EvictExpired:
+		return "expired"
+	case EvictManual:
+		return "manual"
+	case EvictReplaced:
+		return "replaced"
+	default:
+		return "unknown"
+	}
+}
+
+// Stats holds cumulative counters for a cache's lifetime.
+type Stats struct {
+	Hits        uint64
+	Misses      uint64
+	Evictions   uint64
+	Expirations uint64
+}
@@ -0,0 +1,65 @@
+package lru
+
+import "testing"
+
+func TestStatsHitsAndMisses(t *testing.T) {
+	c := New(1024)
+	c.Add("myKey", 1234, 20)
+	c.Get("myKey")
+	c.Get("nonsense")
+
+	stats := c.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Fatalf("TestStatsHitsAndMisses expected 1 hit and 1 miss, got %+v", stats)
+	}
+}
+
+func TestOnEvictedCapacity(t *testing.T) {
+	c := New(20)
+	var gotReason EvictReason
+	var gotKey Key
+	c.OnEvicted = func(key Key, value interface{}, reason EvictReason) {
+		gotKey = key
+		gotReason = reason
+	}
+
+	c.Add("myKey", 1234, 20)
+	c.Add("myKey1", 5678, 20) // evicts "myKey" for capacity
+
+	if gotKey != "myKey" || gotReason != EvictCapacity {
+		t.Fatalf("TestOnEvictedCapacity expected (myKey, EvictCapacity), got (%v, %v)", gotKey, gotReason)
+	}
+	if stats := c.Stats(); stats.Evictions != 1 {
+		t.Fatalf("TestOnEvictedCapacity expected 1 eviction, got %+v", stats)
+	}
+}
+
+func TestOnEvictedReplaced(t *testing.T) {
+	c := New(1024)
+	var gotReason EvictReason
+	c.OnEvicted = func(key Key, value interface{}, reason EvictReason) {
+		gotReason = reason
+	}
+
+	c.Add("myKey", 1234, 20)
+	c.Add("myKey", 5678, 20)
+
+	if gotReason != EvictReplaced {
+		t.Fatalf("TestOnEvictedReplaced expected EvictReplaced, got %v", gotReason)
+	}
+}
+
+func TestOnEvictedManual(t *testing.T) {
+	c := New(1024)
+	var gotReason EvictReason
+	c.OnEvicted = func(key Key, value interface{}, reason EvictReason) {
+		gotReason = reason
+	}
+
+	c.Add("myKey", 1234, 20)
+	c.Remove("myKey")
+
+	if gotReason != EvictManual {
+		t.Fatalf("TestOnEvictedManual expected EvictManual, got %v", gotReason)
+	}
+}
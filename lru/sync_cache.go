@@ -0,0 +1,178 @@
+package lru
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// SyncCache wraps a Cache behind a sync.RWMutex so it can be shared safely
+// across goroutines. Get takes the read lock optimistically and only
+// upgrades to the write lock on a hit, to run the eviction policy's
+// access bookkeeping (moving the entry to the front under PolicyLRU).
+type SyncCache struct {
+	mu sync.RWMutex
+	c  *Cache
+}
+
+// NewSync creates a new SyncCache.
+// If maxSize is zero, the cache has no limit and it's assumed
+// that eviction is done by the caller.
+func NewSync(maxSize int64) *SyncCache {
+	return &SyncCache{c: New(maxSize)}
+}
+
+// NewSyncWithPolicy creates a new SyncCache that evicts using the given
+// PolicyKind (PolicySieve, Policy2Q or PolicyLFU) instead of the default
+// PolicyLRU.
+func NewSyncWithPolicy(maxSize int64, kind PolicyKind) *SyncCache {
+	return &SyncCache{c: NewWithPolicy(maxSize, kind)}
+}
+
+// Add adds a value to the cache.
+func (s *SyncCache) Add(key Key, value interface{}, size int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.c.Add(key, value, size)
+}
+
+// AddWithExpiration adds a value to the cache and sets its expiration explicitly.
+func (s *SyncCache) AddWithExpiration(key Key, value interface{}, size int64, expires time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.c.AddWithExpiration(key, value, size, expires)
+}
+
+// AddWithTTL adds a value to the cache with an explicit per-entry TTL,
+// independent of the cache's TTL field, so short-lived and long-lived
+// entries can share the same cache.
+func (s *SyncCache) AddWithTTL(key Key, value interface{}, size int64, ttl time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.c.AddWithTTL(key, value, size, ttl)
+}
+
+// Get looks up a key's value from the cache. It first checks for a hit
+// under the read lock; if the entry is present and unexpired, the lock is
+// upgraded to the write lock to run the eviction policy's access hook
+// (which moves the entry to the front under PolicyLRU).
+func (s *SyncCache) Get(key Key) (value interface{}, ok bool) {
+	s.mu.RLock()
+	value, ok = s.c.peek(key)
+	s.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	// Re-check under the write lock: the entry may have been removed or
+	// evicted while the lock was upgraded.
+	s.c.promote(key)
+	s.mu.Unlock()
+	return value, true
+}
+
+// Remove removes the provided key from the cache.
+func (s *SyncCache) Remove(key Key) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.c.Remove(key)
+}
+
+// RemoveOldest removes the oldest item from the cache.
+func (s *SyncCache) RemoveOldest() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.c.RemoveOldest()
+}
+
+// RemoveExpired removes expired items from the cache. See Cache.RemoveExpired.
+func (s *SyncCache) RemoveExpired(max int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.c.RemoveExpired(max)
+}
+
+// Len returns the number of items in the cache.
+func (s *SyncCache) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.c.Len()
+}
+
+// Size returns the sum of cache entry sizes currently held.
+func (s *SyncCache) Size() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.c.Size
+}
+
+// Stats returns a snapshot of the cache's cumulative hit/miss/eviction
+// counters.
+func (s *SyncCache) Stats() Stats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.c.Stats()
+}
+
+// SetOnEvicted installs fn as the cache's eviction callback under the
+// write lock, so it can be changed safely from a goroutine other than the
+// one driving Get/Add.
+func (s *SyncCache) SetOnEvicted(fn func(key Key, value interface{}, reason EvictReason)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.c.OnEvicted = fn
+}
+
+// SetCodec installs codec as the cache's SaveTo/LoadFrom serializer under
+// the write lock. A nil codec restores the GobCodec default.
+func (s *SyncCache) SetCodec(codec Codec) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.c.Codec = codec
+}
+
+// SaveTo serializes the cache's contents under the read lock. See
+// Cache.SaveTo.
+func (s *SyncCache) SaveTo(w io.Writer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.c.SaveTo(w)
+}
+
+// LoadFrom replaces the cache's contents under the write lock. See
+// Cache.LoadFrom.
+func (s *SyncCache) LoadFrom(r io.Reader) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.c.LoadFrom(r)
+}
+
+// StartJanitor launches a background goroutine that calls RemoveExpired(0)
+// every interval, so expired entries are reclaimed under the write lock
+// even on caches with a slow write rate. The returned stop func halts the
+// goroutine and does not return until it has exited; it must be called at
+// most once.
+func (s *SyncCache) StartJanitor(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.RemoveExpired(0)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
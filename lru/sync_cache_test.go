@@ -0,0 +1,138 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSyncCacheGetAdd(t *testing.T) {
+	c := NewSync(1024)
+	c.Add("myKey", 1234, 20)
+	if val, ok := c.Get("myKey"); !ok {
+		t.Fatal("TestSyncCacheGetAdd returned no match")
+	} else if val != 1234 {
+		t.Fatalf("TestSyncCacheGetAdd failed. Expected %d, got %v", 1234, val)
+	}
+}
+
+func TestSyncCacheNewWithPolicy(t *testing.T) {
+	c := NewSyncWithPolicy(2, PolicySieve)
+	c.Add("a", 1, 1)
+	c.Add("b", 2, 1)
+	c.Get("a") // marks "a" visited; SIEVE does not reorder the list
+
+	c.Add("c", 3, 1) // "a" is visited, so the hand skips it and evicts "b"
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("TestSyncCacheNewWithPolicy expected b to be evicted")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("TestSyncCacheNewWithPolicy expected a to survive")
+	}
+}
+
+func TestSyncCacheRemove(t *testing.T) {
+	c := NewSync(1024)
+	c.Add("myKey", 1234, 20)
+	c.Remove("myKey")
+	if _, ok := c.Get("myKey"); ok {
+		t.Fatal("TestSyncCacheRemove returned a removed entry")
+	}
+}
+
+func TestSyncCacheConcurrentAccess(t *testing.T) {
+	c := NewSync(0)
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c.Add(i, i, 1)
+			c.Get(i)
+		}(i)
+	}
+	wg.Wait()
+	if c.Len() != 100 {
+		t.Fatalf("TestSyncCacheConcurrentAccess expected 100 entries, got %d", c.Len())
+	}
+}
+
+func TestSyncCacheAddWithTTL(t *testing.T) {
+	c := NewSync(1024)
+	c.AddWithTTL("myKey", 1234, 20, 50*time.Millisecond)
+	if _, ok := c.Get("myKey"); !ok {
+		t.Fatal("TestSyncCacheAddWithTTL returned no match before expiration")
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, ok := c.Get("myKey"); ok {
+		t.Fatal("TestSyncCacheAddWithTTL returned an expired item")
+	}
+}
+
+func TestSyncCacheStats(t *testing.T) {
+	c := NewSync(1024)
+	c.Add("myKey", 1234, 20)
+	c.Get("myKey")
+	c.Get("nonsense")
+
+	stats := c.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Fatalf("TestSyncCacheStats expected 1 hit and 1 miss, got %+v", stats)
+	}
+}
+
+func TestSyncCacheSetOnEvicted(t *testing.T) {
+	c := NewSync(1024)
+	var gotReason EvictReason
+	c.SetOnEvicted(func(key Key, value interface{}, reason EvictReason) {
+		gotReason = reason
+	})
+
+	c.Add("myKey", 1234, 20)
+	c.Remove("myKey")
+
+	if gotReason != EvictManual {
+		t.Fatalf("TestSyncCacheSetOnEvicted expected EvictManual, got %v", gotReason)
+	}
+}
+
+func TestSyncCacheConcurrentGetNoRace(t *testing.T) {
+	c := NewSync(0)
+	c.Add("myKey", 1234, 1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Get("myKey")
+		}()
+	}
+	wg.Wait()
+
+	stats := c.Stats()
+	if stats.Hits != 50 {
+		t.Fatalf("TestSyncCacheConcurrentGetNoRace expected 50 hits, got %d", stats.Hits)
+	}
+}
+
+func TestSyncCacheStartJanitor(t *testing.T) {
+	c := NewSync(1024)
+	c.AddWithTTL("myKey", 1234, 20, 20*time.Millisecond)
+
+	stop := c.StartJanitor(10 * time.Millisecond)
+	defer stop()
+
+	deadline := time.After(500 * time.Millisecond)
+	for {
+		if c.Size() == 0 {
+			return
+		}
+		select {
+		case <-deadline:
+			t.Fatal("TestSyncCacheStartJanitor timed out waiting for the janitor to reclaim the expired entry")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,352 @@
+package lru
+
+import (
+	"container/list"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// Sizer computes the accounting size of a value of type V, so that Add
+// does not need to take an explicit size argument.
+type Sizer[V any] func(value V) int64
+
+// Option configures a TypedCache at construction time.
+type Option[K comparable, V any] func(*TypedCache[K, V])
+
+// WithSizer installs a Sizer used to compute each entry's accounting size.
+// Without a Sizer, every entry counts as size 1, making MaxSize behave as
+// a simple entry-count limit.
+func WithSizer[K comparable, V any](sizer Sizer[V]) Option[K, V] {
+	return func(c *TypedCache[K, V]) {
+		c.sizer = sizer
+	}
+}
+
+// TypedCache is a generics-based LRU cache parameterized by key and value
+// types, avoiding the interface{} boxing of keys and values that Cache
+// requires. It is not safe for concurrent access.
+type TypedCache[K comparable, V any] struct {
+	// MaxSize is the sum of cache entry sizes before
+	// an item is evicted. Zero means no limit.
+	MaxSize int64
+
+	// TTL is the maximum time a single item can remain in cache.
+	// If the value is 0, items do not expire.
+	TTL time.Duration
+
+	// OnEvicted, if set, is called whenever an entry leaves the cache,
+	// whether through eviction, expiration, replacement, or an explicit
+	// Remove. It is called synchronously from the removing call. It is not
+	// called for the entries that LoadFrom discards.
+	OnEvicted func(key K, value V, reason EvictReason)
+
+	// Codec controls how SaveTo/LoadFrom serialize the cache. A nil Codec
+	// defaults to GobCodec.
+	Codec Codec
+
+	sizer      Sizer[V]
+	policy     Policy
+	policyKind PolicyKind
+
+	ll    *list.List
+	cache map[K]*list.Element
+	Size  int64
+
+	// hits, misses, evictions and expirations back Stats. They are updated
+	// with sync/atomic rather than plain increments because peek (and so
+	// Get) is called by SyncCache under only a read lock, which allows
+	// concurrent readers to land on the same counter.
+	hits, misses, evictions, expirations uint64
+}
+
+type typedEntry[K comparable, V any] struct {
+	key     K
+	value   V
+	size    int64
+	expires time.Time
+
+	// visitedBit, promotedBit and freqCount back the optional PolicySieve,
+	// Policy2Q and PolicyLFU eviction strategies; they are unused under
+	// the default PolicyLRU.
+	visitedBit  bool
+	promotedBit bool
+	freqCount   int
+}
+
+func (e *typedEntry[K, V]) expired(now time.Time) bool {
+	return !e.expires.IsZero() && now.After(e.expires)
+}
+
+func (e *typedEntry[K, V]) visited() bool      { return e.visitedBit }
+func (e *typedEntry[K, V]) setVisited(v bool)  { e.visitedBit = v }
+func (e *typedEntry[K, V]) promoted() bool     { return e.promotedBit }
+func (e *typedEntry[K, V]) setPromoted(v bool) { e.promotedBit = v }
+func (e *typedEntry[K, V]) freq() int          { return e.freqCount }
+func (e *typedEntry[K, V]) bump()              { e.freqCount++ }
+
+// NewTyped creates a new TypedCache using PolicyLRU.
+// If maxSize is zero, the cache has no limit and it's assumed
+// that eviction is done by the caller.
+func NewTyped[K comparable, V any](maxSize int64, opts ...Option[K, V]) *TypedCache[K, V] {
+	return NewTypedWithPolicy[K, V](maxSize, PolicyLRU, opts...)
+}
+
+// NewTypedWithPolicy creates a new TypedCache that evicts using the given
+// PolicyKind instead of the default PolicyLRU.
+func NewTypedWithPolicy[K comparable, V any](maxSize int64, kind PolicyKind, opts ...Option[K, V]) *TypedCache[K, V] {
+	c := &TypedCache[K, V]{
+		MaxSize:    maxSize,
+		policy:     newPolicy(kind),
+		policyKind: kind,
+		ll:         list.New(),
+		cache:      make(map[K]*list.Element),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// sizeOf returns the accounting size for value, using the configured
+// Sizer if one was provided, or 1 otherwise.
+func (c *TypedCache[K, V]) sizeOf(value V) int64 {
+	if c.sizer != nil {
+		return c.sizer(value)
+	}
+	return 1
+}
+
+func (c *TypedCache[K, V]) addWithExpiration(key K, value V, size int64, expires time.Time) bool {
+	if c.cache == nil {
+		c.cache = make(map[K]*list.Element)
+		c.ll = list.New()
+	}
+	if c.policy == nil {
+		c.policy = lruPolicy{}
+	}
+
+	if size < 0 {
+		return false
+	}
+
+	// Entry by itself is over the max capacity
+	if c.MaxSize > 0 && size > c.MaxSize {
+		return false
+	}
+
+	// Adding the entry would lead to integer overflow
+	if c.MaxSize > 0 && math.MaxInt64-c.Size < size {
+		return false
+	}
+
+	// If item already exists with this key, update its existing list
+	// element in place and move it to the front, rather than removing and
+	// reallocating, so a replace does not allocate.
+	if ee, ok := c.cache[key]; ok {
+		kv := ee.Value.(*typedEntry[K, V])
+		old := kv.value
+		oldSize := kv.size
+		c.Size -= oldSize
+		kv.value = value
+		kv.size = size
+		kv.expires = expires
+		// A replace is treated as a fresh insert for policy bookkeeping:
+		// the old visited/promoted bits and frequency count belong to the
+		// value being replaced, not the new one, so they must not let a
+		// stale entry outlive a genuinely untouched neighbor.
+		kv.visitedBit = false
+		kv.promotedBit = false
+		kv.freqCount = 0
+		c.Size += size
+		c.ll.MoveToFront(ee)
+		c.policy.onInsert(c.ll, ee)
+		atomic.AddUint64(&c.evictions, 1)
+		if c.OnEvicted != nil {
+			c.OnEvicted(key, old, EvictReplaced)
+		}
+
+		if c.MaxSize <= 0 {
+			return true
+		}
+		for c.Size > c.MaxSize {
+			if c.RemoveExpired(1) == 0 {
+				break
+			}
+		}
+		for c.Size > c.MaxSize {
+			c.RemoveOldest()
+		}
+		return true
+	}
+
+	// Add item to cache
+	e := &typedEntry[K, V]{
+		key:     key,
+		value:   value,
+		size:    size,
+		expires: expires,
+	}
+	ele := c.ll.PushFront(e)
+	c.Size += size
+	c.cache[key] = ele
+	c.policy.onInsert(c.ll, ele)
+
+	if c.MaxSize <= 0 {
+		return true
+	}
+
+	// Remove expired entries
+	for c.Size > c.MaxSize {
+		if c.RemoveExpired(1) == 0 {
+			break
+		}
+	}
+
+	// Remove old entries
+	for c.Size > c.MaxSize {
+		c.RemoveOldest()
+	}
+
+	return true
+}
+
+// Add adds a value to the cache, sizing it via the configured Sizer (or a
+// fixed size of 1 per entry if none was provided). Replacing an existing
+// key reuses its list element, so Add does not allocate in that case.
+func (c *TypedCache[K, V]) Add(key K, value V) bool {
+	var expires time.Time
+	if c.TTL > 0 {
+		expires = time.Now().Add(c.TTL)
+	}
+	return c.addWithExpiration(key, value, c.sizeOf(value), expires)
+}
+
+// AddWithExpiration adds a value to the cache and sets its expiration explicitly.
+func (c *TypedCache[K, V]) AddWithExpiration(key K, value V, expires time.Time) bool {
+	return c.addWithExpiration(key, value, c.sizeOf(value), expires)
+}
+
+// Get looks up a key's value from the cache.
+func (c *TypedCache[K, V]) Get(key K) (value V, ok bool) {
+	value, ok = c.peek(key)
+	if !ok {
+		return
+	}
+	c.promote(key)
+	return value, true
+}
+
+// promote runs the eviction policy's onAccess hook for key, if key is still
+// present. SyncCache calls this directly after re-checking a hit under the
+// write lock.
+func (c *TypedCache[K, V]) promote(key K) {
+	if c.policy == nil {
+		c.policy = lruPolicy{}
+	}
+	if ele, hit := c.cache[key]; hit {
+		c.policy.onAccess(c.ll, ele)
+	}
+}
+
+// peek looks up a key's value without moving it to the front of the
+// recency list, so callers that only hold a read lock can use it. It also
+// records the hit/miss counters reported by Stats.
+func (c *TypedCache[K, V]) peek(key K) (value V, ok bool) {
+	if c.cache != nil {
+		if ele, hit := c.cache[key]; hit {
+			kv := ele.Value.(*typedEntry[K, V])
+			if !kv.expired(time.Now()) {
+				atomic.AddUint64(&c.hits, 1)
+				return kv.value, true
+			}
+		}
+	}
+	atomic.AddUint64(&c.misses, 1)
+	return
+}
+
+// Remove removes the provided key from the cache.
+func (c *TypedCache[K, V]) Remove(key K) {
+	if c.cache == nil {
+		return
+	}
+	if ele, hit := c.cache[key]; hit {
+		c.removeElement(ele, EvictManual)
+	}
+}
+
+// RemoveOldest removes the item chosen by the cache's eviction policy
+// (the least recently used item, under the default PolicyLRU).
+func (c *TypedCache[K, V]) RemoveOldest() {
+	if c.cache == nil {
+		return
+	}
+	if c.policy == nil {
+		c.policy = lruPolicy{}
+	}
+	ele := c.policy.victim(c.ll)
+	if ele != nil {
+		c.removeElement(ele, EvictCapacity)
+	}
+}
+
+// RemoveExpired removes expired items from the cache.
+// Priority for removal is given to the oldest expired items. The max parameter
+// determines the maximum number of items to remove. A value of 0 for max will
+// remove all expired items.
+// Returns the number of items removed.
+func (c *TypedCache[K, V]) RemoveExpired(max int) int {
+	if c.cache == nil {
+		return 0
+	}
+	removed := 0
+	now := time.Now()
+	for e := c.ll.Back(); e != nil; {
+		kv := e.Value.(*typedEntry[K, V])
+		prev := e.Prev()
+		if kv.expired(now) {
+			c.removeElement(e, EvictExpired)
+			removed++
+			if max > 0 && removed == max {
+				break
+			}
+		}
+		e = prev
+	}
+	return removed
+}
+
+func (c *TypedCache[K, V]) removeElement(e *list.Element, reason EvictReason) {
+	c.ll.Remove(e)
+	kv := e.Value.(*typedEntry[K, V])
+	delete(c.cache, kv.key)
+	c.Size -= kv.size
+
+	atomic.AddUint64(&c.evictions, 1)
+	if reason == EvictExpired {
+		atomic.AddUint64(&c.expirations, 1)
+	}
+	if c.OnEvicted != nil {
+		c.OnEvicted(kv.key, kv.value, reason)
+	}
+}
+
+// Len returns the number of items in the cache.
+func (c *TypedCache[K, V]) Len() int {
+	if c.cache == nil {
+		return 0
+	}
+	return c.ll.Len()
+}
+
+// Stats returns a snapshot of the cache's cumulative hit/miss/eviction
+// counters.
+func (c *TypedCache[K, V]) Stats() Stats {
+	return Stats{
+		Hits:        atomic.LoadUint64(&c.hits),
+		Misses:      atomic.LoadUint64(&c.misses),
+		Evictions:   atomic.LoadUint64(&c.evictions),
+		Expirations: atomic.LoadUint64(&c.expirations),
+	}
+}
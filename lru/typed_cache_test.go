@@ -0,0 +1,85 @@
+package lru
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTypedCacheGet(t *testing.T) {
+	c := NewTyped[string, int](1024, WithSizer[string, int](func(int) int64 { return 1024 }))
+	c.Add("myKey", 1234)
+	if val, ok := c.Get("myKey"); !ok {
+		t.Fatal("TestTypedCacheGet returned no match")
+	} else if val != 1234 {
+		t.Fatalf("TestTypedCacheGet expected 1234, got %v", val)
+	}
+	if _, ok := c.Get("nonsense"); ok {
+		t.Fatal("TestTypedCacheGet returned a hit for a missing key")
+	}
+}
+
+func TestTypedCacheNoSizerCountsEntries(t *testing.T) {
+	c := NewTyped[string, int](2)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Add("c", 3)
+	if c.Len() != 2 {
+		t.Fatalf("TestTypedCacheNoSizerCountsEntries expected 2 entries, got %d", c.Len())
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("TestTypedCacheNoSizerCountsEntries returned the evicted entry")
+	}
+}
+
+func TestTypedCacheRemove(t *testing.T) {
+	c := NewTyped[string, int](1024)
+	c.Add("myKey", 1234)
+	c.Remove("myKey")
+	if _, ok := c.Get("myKey"); ok {
+		t.Fatal("TestTypedCacheRemove returned a removed entry")
+	}
+}
+
+func TestTypedCacheAddOverCapacity(t *testing.T) {
+	c := NewTyped[string, int](1024, WithSizer[string, int](func(int) int64 { return 1025 }))
+	if c.Add("myKey", 1234) {
+		t.Fatal("TestTypedCacheAddOverCapacity returned true")
+	}
+}
+
+func TestTypedCacheAddOverflow(t *testing.T) {
+	c := NewTyped[string, int](math.MaxInt64, WithSizer[string, int](func(v int) int64 {
+		if v == 1234 {
+			return math.MaxInt64
+		}
+		return 1
+	}))
+	c.Add("myKey", 1234)
+	if c.Add("myKey1", 5678) {
+		t.Fatal("TestTypedCacheAddOverflow returned true")
+	}
+}
+
+func TestTypedCacheAddReplaceNoAlloc(t *testing.T) {
+	c := NewTyped[string, int](1024)
+	c.Add("myKey", 1234)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		c.Add("myKey", 5678)
+	})
+	if allocs != 0 {
+		t.Fatalf("TestTypedCacheAddReplaceNoAlloc expected 0 allocs/op replacing an existing key, got %v", allocs)
+	}
+}
+
+func TestTypedCacheGetExpired(t *testing.T) {
+	t.Parallel()
+	c := NewTyped[string, int](0)
+	c.TTL = 100 * time.Millisecond
+	c.Add("myKey", 1234)
+	time.Sleep(200 * time.Millisecond)
+	if _, ok := c.Get("myKey"); ok {
+		t.Fatal("TestTypedCacheGetExpired returned an expired item")
+	}
+}